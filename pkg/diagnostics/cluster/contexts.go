@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+
+	kclient "k8s.io/kubernetes/pkg/client"
+	"k8s.io/kubernetes/pkg/client/clientcmd"
+	clientcmdapi "k8s.io/kubernetes/pkg/client/clientcmd/api"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/diagnostics/types"
+)
+
+// ContextClusterDiagnostics is a Diagnostic that, instead of assuming a
+// single KubeClient/OsClient pair, loads a standard kubeconfig and runs a
+// set of cluster diagnostics once per context found in it. This lets
+// operators validate a whole fleet of clusters (dev/stage/prod) with one
+// invocation instead of re-running diagnostics by hand against each one.
+type ContextClusterDiagnostics struct {
+	KubeConfigFile string
+
+	// Diagnostics builds the cluster diagnostics to run against a given
+	// context's clients (e.g. ClusterRouter, ClusterRegistry). It is
+	// called once per context, with clients created for that context.
+	Diagnostics func(kubeClient *kclient.Client, osClient *osclient.Client) []types.Diagnostic
+}
+
+const ContextClusterDiagnosticsName = "ContextClusterDiagnostics"
+
+func (d ContextClusterDiagnostics) Name() string {
+	return ContextClusterDiagnosticsName
+}
+
+func (d ContextClusterDiagnostics) Description() string {
+	return "Run cluster diagnostics once for every context in a kubeconfig"
+}
+
+func (d ContextClusterDiagnostics) CanRun() (bool, error) {
+	if len(d.KubeConfigFile) == 0 {
+		return false, errors.New("must have a kubeconfig file")
+	}
+	if d.Diagnostics == nil {
+		return false, errors.New("must have a set of diagnostics to run per context")
+	}
+	return true, nil
+}
+
+// Check loads every context out of KubeConfigFile and runs Diagnostics
+// against each one's clients in turn. Contexts whose client cannot satisfy
+// a given diagnostic's CanRun (e.g. lacks cluster-admin there) are skipped
+// for that diagnostic rather than aborting the whole run; a context whose
+// clients cannot be built at all is reported and skipped entirely.
+func (d ContextClusterDiagnostics) Check() types.DiagnosticResult {
+	r := types.NewDiagnosticResult(ContextClusterDiagnosticsName)
+
+	config, err := clientcmd.LoadFromFile(d.KubeConfigFile)
+	if err != nil {
+		r.Errorf("DClu2100", err, "Could not load kubeconfig '%s':\n(%T) %[2]v", d.KubeConfigFile, err)
+		return r
+	}
+
+	for contextName := range config.Contexts {
+		tag := fmt.Sprintf("[context %s] ", contextName)
+
+		kubeClient, osClient, err := clientsForContext(*config, contextName)
+		if err != nil {
+			r.Errorf("DClu2101", err, "%sCould not create clients for this context:\n(%T) %[2]v", tag, err)
+			continue
+		}
+
+		for _, diagnostic := range d.Diagnostics(kubeClient, osClient) {
+			can, err := diagnostic.CanRun()
+			if !can {
+				r.Debugf("DClu2102", "%sSkipping %s: %v", tag, diagnostic.Name(), err)
+				continue
+			}
+			mergeTagged(r, diagnostic.Check(), tag)
+		}
+	}
+
+	return r
+}
+
+// clientsForContext builds the Kube and OpenShift clients for a single
+// context out of an already-loaded kubeconfig.
+func clientsForContext(config clientcmdapi.Config, contextName string) (*kclient.Client, *osclient.Client, error) {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	clientConfig, err := clientcmd.NewDefaultClientConfig(config, overrides).ClientConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeClient, err := kclient.New(clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	osClient, err := osclient.New(clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kubeClient, osClient, nil
+}
+
+// mergeTagged copies every message from "from" into "into", prefixing each
+// with tag so the combined result still shows which context it came from.
+func mergeTagged(into types.DiagnosticResult, from types.DiagnosticResult, tag string) {
+	for _, m := range from.Successes() {
+		into.Infof(m.ID, "%s%s", tag, m.EvaluatedText)
+	}
+	for _, m := range from.Warnings() {
+		into.Warnf(m.ID, nil, "%s%s", tag, m.EvaluatedText)
+	}
+	for _, m := range from.Errors() {
+		into.Errorf(m.ID, nil, "%s%s", tag, m.EvaluatedText)
+	}
+}
@@ -0,0 +1,28 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/openshift/origin/pkg/diagnostics/log"
+)
+
+func TestMergeTagged(t *testing.T) {
+	from := &fakeResult{
+		successes: []log.Message{{ID: "S1", EvaluatedText: "all good"}},
+		warnings:  []log.Message{{ID: "W1", EvaluatedText: "hmm"}},
+		errors:    []log.Message{{ID: "E1", EvaluatedText: "broken"}},
+	}
+	into := &fakeResult{}
+
+	mergeTagged(into, from, "[context prod] ")
+
+	if len(into.infofCalls) != 1 || into.infofCalls[0] != "[context prod] all good" {
+		t.Errorf("infofCalls = %v, want one tagged success message", into.infofCalls)
+	}
+	if len(into.warnfCalls) != 1 || into.warnfCalls[0] != "[context prod] hmm" {
+		t.Errorf("warnfCalls = %v, want one tagged warning message", into.warnfCalls)
+	}
+	if len(into.errorfCalls) != 1 || into.errorfCalls[0] != "[context prod] broken" {
+		t.Errorf("errorfCalls = %v, want one tagged error message", into.errorfCalls)
+	}
+}
@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/openshift/origin/pkg/diagnostics/log"
+)
+
+func sprintf(format string, args ...interface{}) string { return fmt.Sprintf(format, args...) }
+
+// fakeResult is a minimal types.DiagnosticResult double for exercising
+// mergeTagged without a real diagnostic run.
+type fakeResult struct {
+	name      string
+	successes []log.Message
+	warnings  []log.Message
+	errors    []log.Message
+
+	infofCalls  []string
+	warnfCalls  []string
+	errorfCalls []string
+}
+
+func (f *fakeResult) Name() string             { return f.name }
+func (f *fakeResult) Successes() []log.Message { return f.successes }
+func (f *fakeResult) Warnings() []log.Message  { return f.warnings }
+func (f *fakeResult) Errors() []log.Message    { return f.errors }
+
+func (f *fakeResult) Debugf(id, format string, args ...interface{}) {}
+func (f *fakeResult) Infof(id, format string, args ...interface{}) {
+	f.infofCalls = append(f.infofCalls, sprintf(format, args...))
+}
+func (f *fakeResult) Warnf(id string, err error, format string, args ...interface{}) {
+	f.warnfCalls = append(f.warnfCalls, sprintf(format, args...))
+}
+func (f *fakeResult) Errorf(id string, err error, format string, args ...interface{}) {
+	f.errorfCalls = append(f.errorfCalls, sprintf(format, args...))
+}
+func (f *fakeResult) Infot(id, template string, hash log.Hash)                    {}
+func (f *fakeResult) Warnt(id string, err error, template string, hash log.Hash)  {}
+func (f *fakeResult) Errort(id string, err error, template string, hash log.Hash) {}
@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"os"
+
+	kclient "k8s.io/kubernetes/pkg/client"
+
+	osclient "github.com/openshift/origin/pkg/client"
+)
+
+// InClusterEnvVar is the environment variable the kubelet sets in every pod,
+// pointing at the apiserver the pod's service account token is valid for.
+// Its presence is how we detect that diagnostics are running as a pod
+// rather than being driven from an operator's workstation.
+const InClusterEnvVar = "KUBERNETES_SERVICE_HOST"
+
+// RunningInCluster reports whether the process appears to be running as a
+// pod, based on the service account environment variables the kubelet
+// injects.
+func RunningInCluster() bool {
+	return len(os.Getenv(InClusterEnvVar)) > 0
+}
+
+// NewInClusterDiagnostics builds the Kube and OpenShift clients from the
+// in-cluster config (the mounted service account token and CA bundle)
+// instead of a kubeconfig file, so a diagnostics image can be shipped as a
+// DaemonSet or Job that self-configures without an admin kubeconfig ever
+// having to reach a node.
+func NewInClusterDiagnostics() (*kclient.Client, *osclient.Client, error) {
+	clientConfig, err := kclient.InClusterConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeClient, err := kclient.New(clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	osClient, err := osclient.New(clientConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kubeClient, osClient, nil
+}
@@ -1,13 +1,9 @@
 package cluster
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
-	"io"
 	"reflect"
-	"regexp"
-	"time"
 
 	kapi "k8s.io/kubernetes/pkg/api"
 	kerrs "k8s.io/kubernetes/pkg/api/errors"
@@ -19,6 +15,7 @@ import (
 	osclient "github.com/openshift/origin/pkg/client"
 	osapi "github.com/openshift/origin/pkg/deploy/api"
 	"github.com/openshift/origin/pkg/diagnostics/log"
+	"github.com/openshift/origin/pkg/diagnostics/podlog"
 	"github.com/openshift/origin/pkg/diagnostics/types"
 )
 
@@ -26,6 +23,12 @@ import (
 type ClusterRouter struct {
 	KubeClient *kclient.Client
 	OsClient   *osclient.Client
+
+	// RouterIP overrides the address routes are probed through. Normally
+	// this is discovered from the router Service's external IP or load
+	// balancer ingress, but split-horizon DNS setups may need a different
+	// address than what public DNS would resolve the route hosts to.
+	RouterIP string
 }
 
 const (
@@ -61,27 +64,6 @@ problem with getting records. The error was:
 	clRtNoPods = `
 The "%s" DeploymentConfig exists but has no running pods, so it
 is not available. Apps will not be externally accessible via the router.`
-
-	clRtPodLog = `
-Failed to read the logs for the "{{.podName}}" pod belonging to
-the router deployment. This is not a problem by itself but prevents
-diagnostics from looking for errors in those logs. The error encountered
-was:
-{{.error}}`
-
-	clRtPodConn = `
-Recent pod logs for the "{{.podName}}" pod belonging to
-the router deployment indicated a problem requesting route information
-from the master. This prevents the router from functioning, so
-applications will not be externally accessible via the router.
-
-There are many reasons for this request to fail, including invalid
-credentials, DNS failures, master outages, and so on. Examine the
-following error message from the router pod logs to determine the
-cause of the problem:
-
-{{.reason}}
-Time: {{.timestamp}}`
 )
 
 func (d *ClusterRouter) Name() string {
@@ -123,6 +105,10 @@ func (d *ClusterRouter) Check() types.DiagnosticResult {
 			}
 		}
 	}
+	// The pod can be running and log-clean while external traffic still
+	// cannot reach applications (firewall, SNI, certificate issues), so
+	// also probe the actual data path through the router.
+	d.checkRouteReachability(r)
 	return r
 }
 
@@ -162,59 +148,10 @@ func (d *ClusterRouter) getRouterPods(dc *osapi.DeploymentConfig, r types.Diagno
 	return pods
 }
 
-// It's like a ReadCloser that gives back lines of text and you still have to Close().
-type lineScanner struct {
-	Scanner    *bufio.Scanner
-	ReadCloser io.ReadCloser
-}
-
-func (s *lineScanner) Scan() bool   { return s.Scanner.Scan() }
-func (s *lineScanner) Text() string { return s.Scanner.Text() }
-func (s *lineScanner) Close() error { return s.ReadCloser.Close() }
-
-func (d *ClusterRouter) getPodLogScanner(pod *kapi.Pod) (*lineScanner, error) {
-	readCloser, err := d.KubeClient.RESTClient.Get().
-		Namespace(pod.ObjectMeta.Namespace).
-		Name(pod.ObjectMeta.Name).
-		Resource("pods").SubResource("log").
-		Param("follow", "false").
-		Param("container", pod.Spec.Containers[0].Name).
-		Stream()
-	if err != nil {
-		return nil, err
-	}
-	return &lineScanner{bufio.NewScanner(readCloser), readCloser}, nil
-}
-
-// http://golang.org/pkg/time/#Parse
-// reference time is Mon Jan 2 15:04:05 -0700 MST 2006
-var referenceTimestampLayout = "2006-01-02T15:04:05.000000000Z"
-
+// checkRouterLogs scans a router pod's logs for known-bad patterns (lost
+// connection to the master, DNS failures, TLS problems, etc.) using the
+// shared podlog engine, so the same pattern set and scanning logic can be
+// reused by other diagnostics instead of each hardcoding its own regex.
 func (d *ClusterRouter) checkRouterLogs(pod *kapi.Pod, r types.DiagnosticResult) {
-	scanner, err := d.getPodLogScanner(pod)
-	if err != nil {
-		r.Warnt("DClu2008", err, clRtPodLog, log.Hash{
-			"error":   fmt.Sprintf("(%T) %[1]v", err),
-			"podName": pod.ObjectMeta.Name,
-		})
-		return
-	}
-	defer scanner.Close()
-
-	for scanner.Scan() {
-		matches := regexp.MustCompile(`^(\S+).*Failed to list \*api.Route: (.*)`).FindStringSubmatch(scanner.Text())
-		if len(matches) > 0 {
-			stamp, err := time.Parse(referenceTimestampLayout, matches[1])
-			// router checks every second. error only if failure is recent.
-			// of course... we cannot always trust the local clock.
-			if err == nil && time.Since(stamp).Seconds() < 30.0 {
-				r.Errort("DClu2009", nil, clRtPodConn, log.Hash{
-					"reason":    matches[2],
-					"timestamp": matches[1],
-					"podName":   pod.ObjectMeta.Name,
-				})
-				break
-			}
-		}
-	}
+	podlog.ScanPod(d.KubeClient, pod, podlog.DefaultPatterns, r)
 }
@@ -0,0 +1,191 @@
+package cluster
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+
+	"github.com/openshift/origin/pkg/diagnostics/types"
+)
+
+const (
+	// routeProbeWorkers bounds how many routes are probed concurrently, so
+	// a cluster with thousands of routes doesn't open thousands of sockets
+	// at once.
+	routeProbeWorkers = 10
+	routeProbeTimeout = 5 * time.Second
+)
+
+// routeProbeResult is the outcome of probing a single route through the
+// router.
+type routeProbeResult struct {
+	host     string
+	status   int
+	duration time.Duration
+	err      error
+}
+
+// checkRouteReachability lists every Route in the cluster and issues an
+// HTTP(S) HEAD request for each through the router's external address,
+// reporting per-route latency and status as diagnostics. Wildcard and
+// passthrough routes are skipped, since the former have no single host to
+// probe and the latter terminate TLS at the backend rather than the router.
+func (d *ClusterRouter) checkRouteReachability(r types.DiagnosticResult) {
+	routerAddr, err := d.getRouterAddress(r)
+	if err != nil || len(routerAddr) == 0 {
+		return
+	}
+
+	routes, err := d.OsClient.Routes(kapi.NamespaceAll).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		r.Errorf("DClu2210", err, "Could not list routes to probe their reachability: (%T) %[1]v", err)
+		return
+	}
+
+	// Partition and log skips synchronously, before any goroutine exists,
+	// so r is only ever written to from this one goroutine at this point.
+	toProbe := make([]routeapi.Route, 0, len(routes.Items))
+	for _, route := range routes.Items {
+		if skipRouteProbe(route) {
+			r.Debugf("DClu2211", "Skipping reachability check for route '%s' (wildcard or passthrough)", route.ObjectMeta.Name)
+			continue
+		}
+		toProbe = append(toProbe, route)
+	}
+
+	jobs := make(chan routeapi.Route)
+	results := make(chan routeProbeResult)
+
+	workers := routeProbeWorkers
+	if len(toProbe) < workers {
+		workers = len(toProbe)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for route := range jobs {
+				results <- probeRoute(route, routerAddr)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	go func() {
+		defer close(jobs)
+		for _, route := range toProbe {
+			jobs <- route
+		}
+	}()
+
+	// Only this goroutine writes to r from here on, so no synchronization
+	// is needed around these calls.
+	for result := range results {
+		switch {
+		case result.err != nil:
+			r.Errorf("DClu2212", result.err, "Route '%s' did not respond through the router: (%[2]T) %[2]v", result.host, result.err)
+		case result.status >= 500:
+			r.Warnf("DClu2213", nil, "Route '%s' responded through the router with status %d after %v", result.host, result.status, result.duration)
+		default:
+			r.Infof("DClu2214", "Route '%s' responded through the router with status %d after %v", result.host, result.status, result.duration)
+		}
+	}
+}
+
+// skipRouteProbe excludes routes that checkRouteReachability can't
+// meaningfully probe: wildcard routes have no single host to request, and
+// passthrough routes terminate TLS at the backend rather than the router,
+// so the router can't be blamed for a handshake failure on them.
+func skipRouteProbe(route routeapi.Route) bool {
+	if strings.HasPrefix(route.Spec.Host, "*.") {
+		return true
+	}
+	if route.Spec.TLS != nil && route.Spec.TLS.Termination == routeapi.TLSTerminationPassthrough {
+		return true
+	}
+	return false
+}
+
+// getRouterAddress determines the address route probes should be sent to:
+// RouterIP if the caller set one (for split-horizon DNS), otherwise the
+// router Service's external IP or load balancer ingress.
+func (d *ClusterRouter) getRouterAddress(r types.DiagnosticResult) (string, error) {
+	if len(d.RouterIP) > 0 {
+		return d.RouterIP, nil
+	}
+
+	svc, err := d.KubeClient.Services(kapi.NamespaceDefault).Get(routerName)
+	if err != nil {
+		r.Warnf("DClu2215", err, "Could not find the '%s' Service to determine its external address, so route reachability will not be checked: (%[2]T) %[2]v", routerName, err)
+		return "", err
+	}
+
+	if len(svc.Spec.ExternalIPs) > 0 {
+		return svc.Spec.ExternalIPs[0], nil
+	}
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if len(ingress.IP) > 0 {
+			return ingress.IP, nil
+		}
+		if len(ingress.Hostname) > 0 {
+			return ingress.Hostname, nil
+		}
+	}
+
+	r.Warnf("DClu2216", nil, "The '%s' Service has no external IP or load balancer ingress, so route reachability will not be checked. Set RouterIP to override this for split-horizon DNS setups.", routerName)
+	return "", nil
+}
+
+// probeRoute issues a single bounded-timeout HEAD request for route through
+// routerAddr, resolving the route's host to that address instead of
+// whatever DNS would otherwise return.
+func probeRoute(route routeapi.Route, routerAddr string) routeProbeResult {
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+
+	client := &http.Client{
+		Timeout: routeProbeTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			Dial: func(network, addr string) (net.Conn, error) {
+				_, port, err := net.SplitHostPort(addr)
+				if err != nil {
+					port = "80"
+					if scheme == "https" {
+						port = "443"
+					}
+				}
+				return net.DialTimeout(network, net.JoinHostPort(routerAddr, port), routeProbeTimeout)
+			},
+		},
+	}
+
+	req, err := http.NewRequest("HEAD", fmt.Sprintf("%s://%s/", scheme, route.Spec.Host), nil)
+	if err != nil {
+		return routeProbeResult{host: route.Spec.Host, err: err}
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return routeProbeResult{host: route.Spec.Host, duration: duration, err: err}
+	}
+	defer resp.Body.Close()
+	return routeProbeResult{host: route.Spec.Host, status: resp.StatusCode, duration: duration}
+}
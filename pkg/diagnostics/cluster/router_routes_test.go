@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"testing"
+
+	routeapi "github.com/openshift/origin/pkg/route/api"
+)
+
+func TestSkipRouteProbe(t *testing.T) {
+	tests := []struct {
+		name  string
+		route routeapi.Route
+		skip  bool
+	}{
+		{
+			name:  "normal route",
+			route: routeapi.Route{Spec: routeapi.RouteSpec{Host: "app.example.com"}},
+			skip:  false,
+		},
+		{
+			name:  "wildcard route",
+			route: routeapi.Route{Spec: routeapi.RouteSpec{Host: "*.example.com"}},
+			skip:  true,
+		},
+		{
+			name: "passthrough route",
+			route: routeapi.Route{Spec: routeapi.RouteSpec{
+				Host: "app.example.com",
+				TLS:  &routeapi.TLSConfig{Termination: routeapi.TLSTerminationPassthrough},
+			}},
+			skip: true,
+		},
+		{
+			name: "edge-terminated route",
+			route: routeapi.Route{Spec: routeapi.RouteSpec{
+				Host: "app.example.com",
+				TLS:  &routeapi.TLSConfig{Termination: routeapi.TLSTerminationEdge},
+			}},
+			skip: false,
+		},
+	}
+
+	for _, test := range tests {
+		if got := skipRouteProbe(test.route); got != test.skip {
+			t.Errorf("%s: skipRouteProbe() = %v, want %v", test.name, got, test.skip)
+		}
+	}
+}
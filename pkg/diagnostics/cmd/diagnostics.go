@@ -0,0 +1,184 @@
+// Package cmd wires the diagnostics library into a runnable CLI command.
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	kclient "k8s.io/kubernetes/pkg/client"
+
+	osclient "github.com/openshift/origin/pkg/client"
+	"github.com/openshift/origin/pkg/diagnostics/cluster"
+	"github.com/openshift/origin/pkg/diagnostics/types"
+)
+
+const (
+	OutputText  = "text"
+	OutputJSON  = "json"
+	OutputJUnit = "junit"
+)
+
+// Options holds the flags for the diagnostics command.
+type Options struct {
+	KubeConfigFile string
+	InCluster      bool
+	Output         string
+
+	Out io.Writer
+}
+
+// NewCommandDiagnostics builds the `diagnostics` command, which runs the
+// cluster diagnostics (ClusterRouter and friends) either once per context
+// in a kubeconfig, or, with --in-cluster, against the in-cluster config
+// mounted into the pod so a diagnostics image never needs an admin
+// kubeconfig shipped to it.
+func NewCommandDiagnostics(out io.Writer) *cobra.Command {
+	o := &Options{Out: out}
+
+	cmd := &cobra.Command{
+		Use:   "diagnostics",
+		Short: "Diagnose common cluster problems",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run()
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&o.KubeConfigFile, "config", "", "path to the kubeconfig file to enumerate contexts from")
+	flags.BoolVar(&o.InCluster, "in-cluster", cluster.RunningInCluster(), "build diagnostics clients from the in-cluster config instead of a kubeconfig")
+	flags.StringVar(&o.Output, "output", OutputText, "output format for diagnostic results: text, json, or junit")
+
+	return cmd
+}
+
+// Run executes the configured diagnostics and renders the results in the
+// requested output format, so the same command can be read by a human
+// (text) or consumed by CI tooling and dashboards (json, junit).
+func (o *Options) Run() error {
+	outcomes, err := o.runDiagnostics()
+	if err != nil {
+		return err
+	}
+
+	switch o.Output {
+	case OutputJSON:
+		return o.reportJSON(outcomes)
+	case OutputJUnit:
+		return o.reportJUnit(outcomes)
+	case OutputText, "":
+		return o.reportText(outcomes)
+	default:
+		return fmt.Errorf("unknown --output %q: must be one of text, json, junit", o.Output)
+	}
+}
+
+// diagnosticOutcome pairs a diagnostic's name with whether it actually ran.
+// Result is only meaningful when CanRun is true; a diagnostic that can't
+// run in this environment still needs to be represented in every output
+// format instead of silently disappearing.
+type diagnosticOutcome struct {
+	Name   string
+	CanRun bool
+	Result types.DiagnosticResult
+}
+
+func (o *Options) runDiagnostics() ([]diagnosticOutcome, error) {
+	if o.InCluster {
+		kubeClient, osClient, err := cluster.NewInClusterDiagnostics()
+		if err != nil {
+			return nil, fmt.Errorf("could not build in-cluster diagnostics clients: %v", err)
+		}
+
+		var outcomes []diagnosticOutcome
+		for _, diagnostic := range clusterDiagnostics(kubeClient, osClient) {
+			can, err := diagnostic.CanRun()
+			if !can {
+				outcomes = append(outcomes, diagnosticOutcome{Name: diagnostic.Name(), CanRun: false})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			outcomes = append(outcomes, diagnosticOutcome{Name: diagnostic.Name(), CanRun: true, Result: diagnostic.Check()})
+		}
+		return outcomes, nil
+	}
+
+	if len(o.KubeConfigFile) == 0 {
+		return nil, fmt.Errorf("--config is required unless --in-cluster is set")
+	}
+	d := cluster.ContextClusterDiagnostics{
+		KubeConfigFile: o.KubeConfigFile,
+		Diagnostics:    clusterDiagnostics,
+	}
+	can, err := d.CanRun()
+	if !can {
+		return []diagnosticOutcome{{Name: d.Name(), CanRun: false}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []diagnosticOutcome{{Name: d.Name(), CanRun: true, Result: d.Check()}}, nil
+}
+
+// clusterDiagnostics is the set of cluster diagnostics this command runs
+// for a given pair of clients.
+func clusterDiagnostics(kubeClient *kclient.Client, osClient *osclient.Client) []types.Diagnostic {
+	return []types.Diagnostic{
+		&cluster.ClusterRouter{KubeClient: kubeClient, OsClient: osClient},
+	}
+}
+
+func (o *Options) reportJSON(outcomes []diagnosticOutcome) error {
+	for _, outcome := range outcomes {
+		var raw []byte
+		var err error
+		if outcome.CanRun {
+			raw, err = types.MarshalDiagnosticResult(outcome.Result)
+		} else {
+			raw, err = types.MarshalSkippedDiagnostic(outcome.Name)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(raw))
+	}
+	return nil
+}
+
+func (o *Options) reportJUnit(outcomes []diagnosticOutcome) error {
+	reporter := types.NewJUnitReporter()
+	for _, outcome := range outcomes {
+		reporter.Add(outcome.Name, outcome.CanRun, outcome.Result)
+	}
+	raw, err := reporter.XML()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out, string(raw))
+	return nil
+}
+
+func (o *Options) reportText(outcomes []diagnosticOutcome) error {
+	for _, outcome := range outcomes {
+		fmt.Fprintf(o.Out, "=== %s ===\n", outcome.Name)
+		if !outcome.CanRun {
+			fmt.Fprintln(o.Out, "[skipped] diagnostic cannot run in this environment")
+			continue
+		}
+
+		r := outcome.Result
+		for _, m := range r.Successes() {
+			fmt.Fprintf(o.Out, "[success] %s: %s\n", m.ID, m.EvaluatedText)
+		}
+		for _, m := range r.Warnings() {
+			fmt.Fprintf(o.Out, "[warning] %s: %s\n", m.ID, m.EvaluatedText)
+		}
+		for _, m := range r.Errors() {
+			fmt.Fprintf(o.Out, "[error] %s: %s\n", m.ID, m.EvaluatedText)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package podlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"time"
+)
+
+// patternConfig is the on-disk form of a LogPattern. regexp.Regexp doesn't
+// round-trip through JSON on its own, so user-supplied patterns are read
+// into this shape and compiled into a LogPattern.
+type patternConfig struct {
+	ID             string `json:"id"`
+	Regex          string `json:"regex"`
+	TimestampGroup int    `json:"timestampGroup"`
+	MessageGroup   int    `json:"messageGroup"`
+	MaxAge         string `json:"maxAge"`
+	Severity       string `json:"severity"`
+	Template       string `json:"template"`
+}
+
+// LoadPatterns reads a JSON file of user-defined patterns and returns them
+// together with DefaultPatterns, so operators can teach diagnostics about
+// log signatures specific to their own cluster without losing the built-in
+// pattern set or needing a code change.
+func LoadPatterns(configFile string) ([]LogPattern, error) {
+	raw, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pattern config '%s': %v", configFile, err)
+	}
+
+	var configs []patternConfig
+	if err := json.Unmarshal(raw, &configs); err != nil {
+		return nil, fmt.Errorf("could not parse pattern config '%s': %v", configFile, err)
+	}
+
+	patterns := make([]LogPattern, 0, len(DefaultPatterns)+len(configs))
+	patterns = append(patterns, DefaultPatterns...)
+	for _, c := range configs {
+		regex, err := regexp.Compile(c.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("pattern '%s' in '%s' has an invalid regex: %v", c.ID, configFile, err)
+		}
+		if c.TimestampGroup < 0 || c.MessageGroup < 0 || c.TimestampGroup > regex.NumSubexp() || c.MessageGroup > regex.NumSubexp() {
+			return nil, fmt.Errorf("pattern '%s' in '%s' has a timestampGroup/messageGroup outside its regex's %d capture groups", c.ID, configFile, regex.NumSubexp())
+		}
+		maxAge, err := parseMaxAge(c.MaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("pattern '%s' in '%s' has an invalid maxAge: %v", c.ID, configFile, err)
+		}
+		patterns = append(patterns, LogPattern{
+			ID:             c.ID,
+			Regex:          regex,
+			TimestampGroup: c.TimestampGroup,
+			MessageGroup:   c.MessageGroup,
+			MaxAge:         maxAge,
+			Severity:       parseSeverity(c.Severity),
+			Template:       c.Template,
+		})
+	}
+	return patterns, nil
+}
+
+func parseMaxAge(s string) (time.Duration, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
+
+func parseSeverity(s string) Severity {
+	switch s {
+	case "info":
+		return SeverityInfo
+	case "warn":
+		return SeverityWarn
+	default:
+		return SeverityError
+	}
+}
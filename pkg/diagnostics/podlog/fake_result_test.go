@@ -0,0 +1,26 @@
+package podlog
+
+import "github.com/openshift/origin/pkg/diagnostics/log"
+
+// fakeResult is a minimal types.DiagnosticResult double that records which
+// report methods were called, for asserting on reportMatch's behavior
+// without needing a real diagnostic run.
+type fakeResult struct {
+	debugs  int
+	infos   int
+	warnts  int
+	errorts int
+}
+
+func (f *fakeResult) Name() string             { return "fake" }
+func (f *fakeResult) Successes() []log.Message { return nil }
+func (f *fakeResult) Warnings() []log.Message  { return nil }
+func (f *fakeResult) Errors() []log.Message    { return nil }
+
+func (f *fakeResult) Debugf(id, format string, args ...interface{})                   { f.debugs++ }
+func (f *fakeResult) Infof(id, format string, args ...interface{})                    {}
+func (f *fakeResult) Warnf(id string, err error, format string, args ...interface{})  {}
+func (f *fakeResult) Errorf(id string, err error, format string, args ...interface{}) {}
+func (f *fakeResult) Infot(id, template string, hash log.Hash)                        { f.infos++ }
+func (f *fakeResult) Warnt(id string, err error, template string, hash log.Hash)      { f.warnts++ }
+func (f *fakeResult) Errort(id string, err error, template string, hash log.Hash)     { f.errorts++ }
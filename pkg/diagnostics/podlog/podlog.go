@@ -0,0 +1,242 @@
+// Package podlog provides a reusable engine for scanning a pod's logs for
+// known-bad patterns and reporting them as diagnostics. It started as a
+// single regex hardcoded in ClusterRouter's log check and was generalized
+// so other diagnostics (ClusterRegistry, MasterConfigCheck, etc.) can reuse
+// the same scanning and reporting logic against their own pattern sets.
+package podlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	kclient "k8s.io/kubernetes/pkg/client"
+
+	"github.com/openshift/origin/pkg/diagnostics/log"
+	"github.com/openshift/origin/pkg/diagnostics/types"
+)
+
+// Severity controls which DiagnosticResult method a matched LogPattern is
+// reported through.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarn
+	SeverityError
+)
+
+// LogPattern describes one known-bad message to look for in a pod's logs.
+// Regex is matched against each log line; TimestampGroup and MessageGroup
+// name the submatches holding the log line's timestamp and the text to
+// report. MaxAge bounds how recent a match has to be to still be worth
+// reporting (logs rotate and old errors are frequently stale). Template is
+// rendered with {{.reason}}, {{.timestamp}} and {{.podName}}, matching the
+// log.Hash-driven templates already used elsewhere in diagnostics.
+type LogPattern struct {
+	ID             string
+	Regex          *regexp.Regexp
+	TimestampGroup int
+	MessageGroup   int
+	MaxAge         time.Duration
+	Severity       Severity
+	Template       string
+}
+
+// http://golang.org/pkg/time/#Parse
+// reference time is Mon Jan 2 15:04:05 -0700 MST 2006
+const referenceTimestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+const (
+	routerConnTemplate = `
+Recent pod logs for the "{{.podName}}" pod indicated a problem requesting
+route information from the master. This prevents the router from
+functioning, so applications will not be externally accessible via it.
+
+There are many reasons for this request to fail, including invalid
+credentials, DNS failures, master outages, and so on. Examine the
+following error message from the pod logs to determine the cause of
+the problem:
+
+{{.reason}}
+Time: {{.timestamp}}`
+
+	dnsFailureTemplate = `
+Recent pod logs for the "{{.podName}}" pod show a DNS lookup failure.
+This usually means the cluster DNS service is down or the name being
+looked up does not exist.
+
+{{.reason}}
+Time: {{.timestamp}}`
+
+	tlsHandshakeTemplate = `
+Recent pod logs for the "{{.podName}}" pod show a failed TLS handshake.
+This usually indicates a certificate mismatch or an untrusted CA between
+this pod and whatever it is connecting to.
+
+{{.reason}}
+Time: {{.timestamp}}`
+
+	registryAuthTemplate = `
+Recent pod logs for the "{{.podName}}" pod show an authentication failure
+talking to the image registry. Images may fail to pull or push until this
+is resolved.
+
+{{.reason}}
+Time: {{.timestamp}}`
+
+	etcdConnTemplate = `
+Recent pod logs for the "{{.podName}}" pod show a lost connection to
+etcd or the master API server. This pod will not function correctly
+until connectivity is restored.
+
+{{.reason}}
+Time: {{.timestamp}}`
+)
+
+// DefaultPatterns is the pattern set diagnostics use when the caller has no
+// more specific patterns of its own. It covers the most common causes of a
+// pod that is running but not actually doing its job: router credential
+// failures, DNS lookup errors, TLS handshake failures, registry auth
+// errors, and etcd/apiserver connection loss.
+var DefaultPatterns = []LogPattern{
+	{
+		ID:             "PLRouterConn",
+		Regex:          regexp.MustCompile(`^(\S+).*Failed to list \*api.Route: (.*)`),
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         30 * time.Second,
+		Severity:       SeverityError,
+		Template:       routerConnTemplate,
+	},
+	{
+		ID:             "PLDNSFailure",
+		Regex:          regexp.MustCompile(`^(\S+).*(dial tcp: lookup .*: no such host.*)`),
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         time.Minute,
+		Severity:       SeverityWarn,
+		Template:       dnsFailureTemplate,
+	},
+	{
+		ID:             "PLTLSHandshake",
+		Regex:          regexp.MustCompile(`^(\S+).*(tls: .*handshake failure.*)`),
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         time.Minute,
+		Severity:       SeverityError,
+		Template:       tlsHandshakeTemplate,
+	},
+	{
+		ID:             "PLRegistryAuth",
+		Regex:          regexp.MustCompile(`^(\S+).*(unauthorized: authentication required.*)`),
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         time.Minute,
+		Severity:       SeverityError,
+		Template:       registryAuthTemplate,
+	},
+	{
+		ID:             "PLEtcdConn",
+		Regex:          regexp.MustCompile(`^(\S+).*(client: etcd cluster is unavailable or misconfigured.*)`),
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         time.Minute,
+		Severity:       SeverityError,
+		Template:       etcdConnTemplate,
+	},
+}
+
+// lineScanner is like a ReadCloser that gives back lines of text and you
+// still have to Close().
+type lineScanner struct {
+	Scanner    *bufio.Scanner
+	ReadCloser io.ReadCloser
+}
+
+func (s *lineScanner) Scan() bool   { return s.Scanner.Scan() }
+func (s *lineScanner) Text() string { return s.Scanner.Text() }
+func (s *lineScanner) Close() error { return s.ReadCloser.Close() }
+
+func getPodLogScanner(client *kclient.Client, pod *kapi.Pod) (*lineScanner, error) {
+	readCloser, err := client.RESTClient.Get().
+		Namespace(pod.ObjectMeta.Namespace).
+		Name(pod.ObjectMeta.Name).
+		Resource("pods").SubResource("log").
+		Param("follow", "false").
+		Param("container", pod.Spec.Containers[0].Name).
+		Stream()
+	if err != nil {
+		return nil, err
+	}
+	return &lineScanner{bufio.NewScanner(readCloser), readCloser}, nil
+}
+
+// ScanPod reads the logs of pod's first container and checks every line
+// against patterns, reporting each match through r. A read failure is
+// reported as a warning (it prevents scanning but does not by itself mean
+// anything is wrong with the pod).
+func ScanPod(client *kclient.Client, pod *kapi.Pod, patterns []LogPattern, r types.DiagnosticResult) {
+	scanner, err := getPodLogScanner(client, pod)
+	if err != nil {
+		r.Warnt("PL1001", err, podLogReadFailed, log.Hash{
+			"error":   fmt.Sprintf("(%T) %[1]v", err),
+			"podName": pod.ObjectMeta.Name,
+		})
+		return
+	}
+	defer scanner.Close()
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		for _, pattern := range patterns {
+			if matches := pattern.Regex.FindStringSubmatch(line); matches != nil {
+				reportMatch(pattern, matches, pod, r)
+			}
+		}
+	}
+}
+
+const podLogReadFailed = `
+Failed to read the logs for the "{{.podName}}" pod. This is not a
+problem by itself but prevents diagnostics from looking for errors in
+those logs. The error encountered was:
+{{.error}}`
+
+func reportMatch(pattern LogPattern, matches []string, pod *kapi.Pod, r types.DiagnosticResult) {
+	if pattern.MessageGroup < 0 || pattern.TimestampGroup < 0 || pattern.MessageGroup >= len(matches) || pattern.TimestampGroup >= len(matches) {
+		// A user-supplied pattern can name a group the regex doesn't
+		// actually have; don't let that panic the whole diagnostic run.
+		r.Debugf("PL1002", "Pattern '%s' matched but its messageGroup/timestampGroup is out of range for the regex's capture groups", pattern.ID)
+		return
+	}
+
+	reason := matches[pattern.MessageGroup]
+	timestamp := matches[pattern.TimestampGroup]
+
+	if pattern.MaxAge > 0 {
+		stamp, err := time.Parse(referenceTimestampLayout, timestamp)
+		// of course... we cannot always trust the local clock, but if we
+		// can parse it, skip matches that are stale.
+		if err == nil && time.Since(stamp) > pattern.MaxAge {
+			return
+		}
+	}
+
+	hash := log.Hash{
+		"reason":    reason,
+		"timestamp": timestamp,
+		"podName":   pod.ObjectMeta.Name,
+	}
+	switch pattern.Severity {
+	case SeverityInfo:
+		r.Infot(pattern.ID, pattern.Template, hash)
+	case SeverityWarn:
+		r.Warnt(pattern.ID, nil, pattern.Template, hash)
+	default:
+		r.Errort(pattern.ID, nil, pattern.Template, hash)
+	}
+}
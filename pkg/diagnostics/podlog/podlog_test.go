@@ -0,0 +1,174 @@
+package podlog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "30s", want: 30 * time.Second},
+		{in: "not-a-duration", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := parseMaxAge(test.in)
+		if test.wantErr != (err != nil) {
+			t.Errorf("parseMaxAge(%q): err = %v, wantErr %v", test.in, err, test.wantErr)
+			continue
+		}
+		if err == nil && got != test.want {
+			t.Errorf("parseMaxAge(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Severity
+	}{
+		{"info", SeverityInfo},
+		{"warn", SeverityWarn},
+		{"error", SeverityError},
+		{"", SeverityError},
+		{"bogus", SeverityError},
+	}
+	for _, test := range tests {
+		if got := parseSeverity(test.in); got != test.want {
+			t.Errorf("parseSeverity(%q) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestLoadPatternsIncludesDefaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "podlog-test")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "patterns.json")
+	if err := ioutil.WriteFile(configFile, []byte(`[{"id":"custom","regex":"^(\\S+) boom (.*)","timestampGroup":1,"messageGroup":2,"severity":"warn"}]`), 0644); err != nil {
+		t.Fatalf("could not write config file: %v", err)
+	}
+
+	patterns, err := LoadPatterns(configFile)
+	if err != nil {
+		t.Fatalf("LoadPatterns returned an error: %v", err)
+	}
+	if len(patterns) != len(DefaultPatterns)+1 {
+		t.Fatalf("len(patterns) = %d, want %d (DefaultPatterns + 1 custom)", len(patterns), len(DefaultPatterns)+1)
+	}
+	if patterns[len(patterns)-1].ID != "custom" {
+		t.Errorf("last pattern ID = %q, want %q", patterns[len(patterns)-1].ID, "custom")
+	}
+}
+
+func TestLoadPatternsRejectsOutOfRangeGroup(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "messageGroup beyond the regex's capture groups",
+			config: `[{"id":"custom","regex":"^(\\S+) boom$","timestampGroup":1,"messageGroup":5}]`,
+		},
+		{
+			name:   "negative messageGroup",
+			config: `[{"id":"custom","regex":"^(\\S+) boom (.*)$","timestampGroup":1,"messageGroup":-1}]`,
+		},
+		{
+			name:   "negative timestampGroup",
+			config: `[{"id":"custom","regex":"^(\\S+) boom (.*)$","timestampGroup":-1,"messageGroup":2}]`,
+		},
+	}
+
+	for _, test := range tests {
+		dir, err := ioutil.TempDir("", "podlog-test")
+		if err != nil {
+			t.Fatalf("%s: could not create temp dir: %v", test.name, err)
+		}
+		configFile := filepath.Join(dir, "patterns.json")
+		if err := ioutil.WriteFile(configFile, []byte(test.config), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("%s: could not write config file: %v", test.name, err)
+		}
+
+		_, err = LoadPatterns(configFile)
+		os.RemoveAll(dir)
+		if err == nil {
+			t.Errorf("%s: expected LoadPatterns to reject this pattern config", test.name)
+		}
+	}
+}
+
+func TestReportMatchSkipsStaleMatches(t *testing.T) {
+	pattern := LogPattern{
+		ID:             "PLTest",
+		TimestampGroup: 1,
+		MessageGroup:   2,
+		MaxAge:         30 * time.Second,
+		Severity:       SeverityError,
+		Template:       "{{.reason}}",
+	}
+	stale := time.Now().Add(-time.Hour).UTC().Format(referenceTimestampLayout)
+	matches := []string{"whole match", stale, "some problem"}
+
+	r := &fakeResult{}
+	reportMatch(pattern, matches, &kapi.Pod{}, r)
+
+	if r.errorts != 0 {
+		t.Errorf("expected a stale match not to be reported, but Errort was called %d times", r.errorts)
+	}
+}
+
+func TestReportMatchBoundsChecksGroups(t *testing.T) {
+	pattern := LogPattern{
+		ID:             "PLTest",
+		TimestampGroup: 1,
+		MessageGroup:   5, // out of range for matches below
+		Severity:       SeverityError,
+		Template:       "{{.reason}}",
+	}
+	matches := []string{"whole match", "2016-01-01T00:00:00.000000000Z"}
+
+	r := &fakeResult{}
+	reportMatch(pattern, matches, &kapi.Pod{}, r) // must not panic
+
+	if r.debugs == 0 {
+		t.Error("expected an out-of-range group to be reported via Debugf")
+	}
+	if r.errorts != 0 {
+		t.Error("expected an out-of-range group not to reach the Errort call")
+	}
+}
+
+func TestReportMatchBoundsChecksNegativeGroups(t *testing.T) {
+	pattern := LogPattern{
+		ID:             "PLTest",
+		TimestampGroup: 1,
+		MessageGroup:   -1,
+		Severity:       SeverityError,
+		Template:       "{{.reason}}",
+	}
+	matches := []string{"whole match", "2016-01-01T00:00:00.000000000Z", "some problem"}
+
+	r := &fakeResult{}
+	reportMatch(pattern, matches, &kapi.Pod{}, r) // must not panic
+
+	if r.debugs == 0 {
+		t.Error("expected a negative messageGroup to be reported via Debugf")
+	}
+	if r.errorts != 0 {
+		t.Error("expected a negative messageGroup not to reach the Errort call")
+	}
+}
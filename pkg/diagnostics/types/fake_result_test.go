@@ -0,0 +1,26 @@
+package types
+
+import "github.com/openshift/origin/pkg/diagnostics/log"
+
+// fakeResult is a minimal DiagnosticResult double for exercising code that
+// consumes a result's messages (MarshalDiagnosticResult, JUnitReporter)
+// without needing a real diagnostic run.
+type fakeResult struct {
+	name      string
+	successes []log.Message
+	warnings  []log.Message
+	errors    []log.Message
+}
+
+func (f *fakeResult) Name() string             { return f.name }
+func (f *fakeResult) Successes() []log.Message { return f.successes }
+func (f *fakeResult) Warnings() []log.Message  { return f.warnings }
+func (f *fakeResult) Errors() []log.Message    { return f.errors }
+
+func (f *fakeResult) Debugf(id, format string, args ...interface{})                   {}
+func (f *fakeResult) Infof(id, format string, args ...interface{})                    {}
+func (f *fakeResult) Warnf(id string, err error, format string, args ...interface{})  {}
+func (f *fakeResult) Errorf(id string, err error, format string, args ...interface{}) {}
+func (f *fakeResult) Infot(id, template string, hash log.Hash)                        {}
+func (f *fakeResult) Warnt(id string, err error, template string, hash log.Hash)      {}
+func (f *fakeResult) Errort(id string, err error, template string, hash log.Hash)     {}
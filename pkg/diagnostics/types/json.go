@@ -0,0 +1,52 @@
+package types
+
+import "encoding/json"
+
+// jsonResult is the wire shape of a DiagnosticResult: its name plus every
+// message it collected, tagged with the level it was logged at. Diagnostic
+// consumers that only understand JSON (dashboards, CI tooling) can read
+// this without any knowledge of DiagnosticResult's internals.
+type jsonResult struct {
+	Name    string            `json:"name"`
+	Entries []jsonResultEntry `json:"entries"`
+}
+
+type jsonResultEntry struct {
+	Level string `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// MarshalDiagnosticResult renders a DiagnosticResult as a machine-readable
+// record, so that results emitted by ClusterRouter.Check, NodeConfigCheck.Check,
+// and their siblings can be consumed by dashboards and CI pipelines in
+// addition to being printed as human-readable text.
+//
+// DiagnosticResult is an interface (like Diagnostic itself), so this can't
+// be a MarshalJSON method on it directly; callers that want JSON from a
+// result call this function instead.
+func MarshalDiagnosticResult(r DiagnosticResult) ([]byte, error) {
+	out := jsonResult{Name: r.Name()}
+	for _, m := range r.Successes() {
+		out.Entries = append(out.Entries, jsonResultEntry{Level: "success", ID: m.ID, Text: m.EvaluatedText})
+	}
+	for _, m := range r.Warnings() {
+		out.Entries = append(out.Entries, jsonResultEntry{Level: "warning", ID: m.ID, Text: m.EvaluatedText})
+	}
+	for _, m := range r.Errors() {
+		out.Entries = append(out.Entries, jsonResultEntry{Level: "error", ID: m.ID, Text: m.EvaluatedText})
+	}
+	return json.Marshal(out)
+}
+
+// MarshalSkippedDiagnostic renders the fact that a diagnostic's CanRun
+// returned false, in the same shape as MarshalDiagnosticResult, so that a
+// diagnostic which didn't run still shows up in JSON output instead of
+// silently disappearing from it.
+func MarshalSkippedDiagnostic(name string) ([]byte, error) {
+	out := jsonResult{
+		Name:    name,
+		Entries: []jsonResultEntry{{Level: "skipped", Text: "diagnostic cannot run in this environment"}},
+	}
+	return json.Marshal(out)
+}
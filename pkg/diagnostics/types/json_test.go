@@ -0,0 +1,58 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openshift/origin/pkg/diagnostics/log"
+)
+
+func TestMarshalDiagnosticResult(t *testing.T) {
+	result := &fakeResult{
+		name:      "ClusterRouter",
+		successes: []log.Message{{ID: "DClu1", EvaluatedText: "all good"}},
+		warnings:  []log.Message{{ID: "DClu2", EvaluatedText: "hmm"}},
+		errors:    []log.Message{{ID: "DClu3", EvaluatedText: "broken"}},
+	}
+
+	raw, err := MarshalDiagnosticResult(result)
+	if err != nil {
+		t.Fatalf("MarshalDiagnosticResult returned an error: %v", err)
+	}
+
+	var out jsonResult
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("could not unmarshal output: %v", err)
+	}
+	if out.Name != "ClusterRouter" {
+		t.Errorf("Name = %q, want %q", out.Name, "ClusterRouter")
+	}
+	if len(out.Entries) != 3 {
+		t.Fatalf("len(Entries) = %d, want 3", len(out.Entries))
+	}
+
+	want := map[string]string{"DClu1": "success", "DClu2": "warning", "DClu3": "error"}
+	for _, entry := range out.Entries {
+		if want[entry.ID] != entry.Level {
+			t.Errorf("entry %s: Level = %q, want %q", entry.ID, entry.Level, want[entry.ID])
+		}
+	}
+}
+
+func TestMarshalSkippedDiagnostic(t *testing.T) {
+	raw, err := MarshalSkippedDiagnostic("NodeConfigCheck")
+	if err != nil {
+		t.Fatalf("MarshalSkippedDiagnostic returned an error: %v", err)
+	}
+
+	var out jsonResult
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("could not unmarshal output: %v", err)
+	}
+	if out.Name != "NodeConfigCheck" {
+		t.Errorf("Name = %q, want %q", out.Name, "NodeConfigCheck")
+	}
+	if len(out.Entries) != 1 || out.Entries[0].Level != "skipped" {
+		t.Fatalf("Entries = %+v, want a single skipped entry", out.Entries)
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import "encoding/xml"
+
+// JUnitReporter accumulates DiagnosticResults, grouped by diagnostic name,
+// and renders them as a JUnit-style XML report. This lets a diagnostics run
+// be consumed by CI systems that already understand JUnit output, such as
+// the openshift-e2e circle jobs.
+type JUnitReporter struct {
+	suiteNames []string
+	suites     map[string]*junitSuite
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name    string        `xml:"name,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+	Skipped *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// NewJUnitReporter returns an empty JUnitReporter ready to accumulate
+// results via Add.
+func NewJUnitReporter() *JUnitReporter {
+	return &JUnitReporter{suites: map[string]*junitSuite{}}
+}
+
+// Add records one diagnostic's outcome under its own testsuite. When canRun
+// is false the diagnostic becomes a single <skipped> testcase; otherwise
+// every Errorf becomes a <failure> testcase and every Successes() entry
+// becomes a passing one.
+func (j *JUnitReporter) Add(diagnosticName string, canRun bool, result DiagnosticResult) {
+	suite, ok := j.suites[diagnosticName]
+	if !ok {
+		suite = &junitSuite{Name: diagnosticName}
+		j.suites[diagnosticName] = suite
+		j.suiteNames = append(j.suiteNames, diagnosticName)
+	}
+
+	if !canRun {
+		suite.Tests++
+		suite.Skipped++
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:    diagnosticName,
+			Skipped: &junitSkipped{Message: "diagnostic cannot run in this environment"},
+		})
+		return
+	}
+
+	for _, m := range result.Errors() {
+		suite.Tests++
+		suite.Failures++
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:    m.ID,
+			Failure: &junitFailure{Message: m.ID, Text: m.EvaluatedText},
+		})
+	}
+	for _, m := range result.Successes() {
+		suite.Tests++
+		suite.Cases = append(suite.Cases, junitCase{Name: m.ID})
+	}
+}
+
+// XML renders everything recorded so far as a <testsuites> document.
+func (j *JUnitReporter) XML() ([]byte, error) {
+	doc := junitSuites{}
+	for _, name := range j.suiteNames {
+		doc.Suites = append(doc.Suites, *j.suites[name])
+	}
+	return xml.MarshalIndent(doc, "", "  ")
+}
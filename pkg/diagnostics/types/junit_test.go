@@ -0,0 +1,34 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/diagnostics/log"
+)
+
+func TestJUnitReporterAdd(t *testing.T) {
+	reporter := NewJUnitReporter()
+
+	reporter.Add("ClusterRouter", true, &fakeResult{
+		successes: []log.Message{{ID: "DClu1", EvaluatedText: "ok"}},
+		errors:    []log.Message{{ID: "DClu2", EvaluatedText: "broken"}},
+	})
+	reporter.Add("NodeConfigCheck", false, &fakeResult{})
+
+	raw, err := reporter.XML()
+	if err != nil {
+		t.Fatalf("XML() returned an error: %v", err)
+	}
+	doc := string(raw)
+
+	if !strings.Contains(doc, `name="ClusterRouter"`) {
+		t.Errorf("expected a ClusterRouter testsuite, got:\n%s", doc)
+	}
+	if !strings.Contains(doc, `tests="2" failures="1"`) {
+		t.Errorf("expected ClusterRouter suite to have 2 tests and 1 failure, got:\n%s", doc)
+	}
+	if !strings.Contains(doc, `skipped="1"`) {
+		t.Errorf("expected NodeConfigCheck suite to record a skip, got:\n%s", doc)
+	}
+}